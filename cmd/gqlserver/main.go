@@ -0,0 +1,116 @@
+// Command gqlserver exposes the solutions persisted by a sweep run over a
+// GraphQL endpoint so researchers can explore stored runs without writing
+// AWS CLI queries by hand.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net/http"
+
+	"github.com/Drofff/revsynth-researcher/internal/researcher"
+	"github.com/Drofff/revsynth/circuit"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/handler"
+)
+
+func main() {
+	addr := flag.String("addr", ":8080", "address to listen on")
+	playground := flag.Bool("gql-playground", false, "serve a GraphiQL playground at /playground")
+	ddbEndpoint := flag.String("ddb-endpoint", "", "override the DynamoDB endpoint, e.g. to use DynamoDB Local for CI")
+	flag.Parse()
+
+	ctx := context.Background()
+	repo := researcher.CreateDDBRepository(ctx, *ddbEndpoint)
+	queryable, ok := repo.(researcher.Queryable)
+	if !ok {
+		log.Fatalln("configured repository does not support querying")
+	}
+
+	schema, err := buildSchema(queryable)
+	if err != nil {
+		log.Fatalln("Build schema:", err)
+	}
+
+	h := handler.New(&handler.Config{
+		Schema:   &schema,
+		Pretty:   true,
+		GraphiQL: *playground,
+	})
+
+	http.Handle("/graphql", h)
+	if *playground {
+		http.Handle("/playground", h)
+	}
+
+	log.Println("Listening on", *addr)
+	log.Fatalln(http.ListenAndServe(*addr, nil))
+}
+
+func buildSchema(repo researcher.Queryable) (graphql.Schema, error) {
+	gateType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Gate",
+		Fields: graphql.Fields{
+			"typeName": &graphql.Field{Type: graphql.String, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(circuit.Gate).TypeName(), nil
+			}},
+			"targetBits": &graphql.Field{Type: graphql.NewList(graphql.Int), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(circuit.Gate).TargetBits(), nil
+			}},
+			"controlBits": &graphql.Field{Type: graphql.NewList(graphql.Int), Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return p.Source.(circuit.Gate).ControlBits(), nil
+			}},
+		},
+	})
+
+	solutionType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Solution",
+		Fields: graphql.Fields{
+			"quantumCost":  &graphql.Field{Type: graphql.Int},
+			"targetVector": &graphql.Field{Type: graphql.NewList(graphql.Int)},
+			"gates":        &graphql.Field{Type: graphql.NewList(gateType)},
+		},
+	})
+
+	queryType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"getSolutionById": &graphql.Field{
+				Type: solutionType,
+				Args: graphql.FieldConfigArgument{
+					"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return repo.GetSolutionByID(p.Context, p.Args["id"].(string))
+				},
+			},
+			"querySolutions": &graphql.Field{
+				Type: graphql.NewList(solutionType),
+				Args: graphql.FieldConfigArgument{
+					"minQuantumCost": &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 0},
+					"maxQuantumCost": &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 1 << 30},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return repo.QuerySolutions(p.Context, p.Args["minQuantumCost"].(int), p.Args["maxQuantumCost"].(int))
+				},
+			},
+			"getSolutionsByTargetVector": &graphql.Field{
+				Type: graphql.NewList(solutionType),
+				Args: graphql.FieldConfigArgument{
+					"targetVector": &graphql.ArgumentConfig{Type: graphql.NewList(graphql.Int)},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					raw := p.Args["targetVector"].([]interface{})
+					vector := make([]int, len(raw))
+					for i, v := range raw {
+						vector[i] = v.(int)
+					}
+					return repo.GetSolutionsByTargetVector(p.Context, vector)
+				},
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: queryType})
+}
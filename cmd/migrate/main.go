@@ -0,0 +1,57 @@
+// Command migrate copies every solution from one storage backend to
+// another, e.g. exporting a DynamoDB table to a local jsonl file for
+// offline analysis.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+
+	"github.com/Drofff/revsynth-researcher/internal/researcher"
+)
+
+func main() {
+	fromStorage := flag.String("from", "ddb", "source storage backend (ddb|jsonl|sqlite|s3)")
+	toStorage := flag.String("to", "jsonl", "destination storage backend (ddb|jsonl|sqlite|s3)")
+	fromJSONL := flag.String("from-jsonl-path", "solutions.jsonl", "source jsonl path")
+	toJSONL := flag.String("to-jsonl-path", "solutions.jsonl", "destination jsonl path")
+	fromSQLite := flag.String("from-sqlite-path", "solutions.db", "source sqlite path")
+	toSQLite := flag.String("to-sqlite-path", "solutions.db", "destination sqlite path")
+	fromS3Bucket := flag.String("from-s3-bucket", "", "source s3 bucket")
+	fromS3Prefix := flag.String("from-s3-prefix", "", "source s3 prefix")
+	toS3Bucket := flag.String("to-s3-bucket", "", "destination s3 bucket")
+	toS3Prefix := flag.String("to-s3-prefix", "", "destination s3 prefix")
+	ddbEndpoint := flag.String("ddb-endpoint", "", "override the DynamoDB endpoint, e.g. to use DynamoDB Local for CI")
+	flag.Parse()
+
+	ctx := context.Background()
+
+	from, err := researcher.CreateRepository(ctx, *fromStorage, researcher.StorageConfig{
+		JSONLPath:   *fromJSONL,
+		SQLitePath:  *fromSQLite,
+		S3Bucket:    *fromS3Bucket,
+		S3Prefix:    *fromS3Prefix,
+		DDBEndpoint: *ddbEndpoint,
+	})
+	if err != nil {
+		log.Fatalln("Configure source repository:", err)
+	}
+
+	to, err := researcher.CreateRepository(ctx, *toStorage, researcher.StorageConfig{
+		JSONLPath:   *toJSONL,
+		SQLitePath:  *toSQLite,
+		S3Bucket:    *toS3Bucket,
+		S3Prefix:    *toS3Prefix,
+		DDBEndpoint: *ddbEndpoint,
+	})
+	if err != nil {
+		log.Fatalln("Configure destination repository:", err)
+	}
+
+	n, err := researcher.Migrate(ctx, from, to)
+	if err != nil {
+		log.Fatalln("Migrate:", err)
+	}
+	log.Println("Migrated", n, "solutions")
+}
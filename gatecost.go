@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/Drofff/revsynth/circuit"
+)
+
+// toffoliMCTControlsThreshold is the control count at or below which
+// "toffoli" is priced as a plain (possibly 2-control) Toffoli rather than
+// a generalized MCT gate. In this version of the circuit library,
+// NewToffoliGateFactory has no control bits limit, so a "toffoli" gate
+// with more controls than an ordinary Toffoli has is actually a
+// generalized multi-controlled-X gate, not a flat-cost gate.
+const toffoliMCTControlsThreshold = 2
+
+// GateCostModel maps a gate's TypeName to its cost in NCV (NOT-CNOT-V)
+// gates, the common unit quantum cost is expressed in for reversible
+// circuits. "toffoli" is priced per-gate rather than from this table once
+// its control count exceeds toffoliMCTControlsThreshold; see mctCost.
+type GateCostModel map[string]int
+
+// defaultGateCostModel is the NCV-based cost table used when the input
+// file doesn't override a gate's cost: CNOT=1, Toffoli=5, Fredkin=5.
+func defaultGateCostModel() GateCostModel {
+	return GateCostModel{
+		"cnot":    1,
+		"toffoli": 5,
+		"fredkin": 5,
+	}
+}
+
+// newGateCostModel builds a GateCostModel starting from the defaults and
+// overlaying any costs the sweep's input file specifies.
+func newGateCostModel(overrides map[string]int) GateCostModel {
+	model := defaultGateCostModel()
+	for typeName, cost := range overrides {
+		model[typeName] = cost
+	}
+	return model
+}
+
+// mctCost is the textbook NCV gate count for a generalized MCT gate with
+// n control lines: such a gate decomposes into 2*(2^(n-1) - 1) Toffolis,
+// each costing toffoliCost NCV gates (the model's own "toffoli" entry, so
+// an overridden Toffoli cost is reflected in MCT-derived costs too).
+func mctCost(controls int, toffoliCost int) int {
+	toffolis := 2 * ((1 << (controls - 1)) - 1)
+	return toffolis * toffoliCost
+}
+
+// CalcQuantumCost totals the cost of gates under this model. A gate type
+// with no entry in the model is reported as an error rather than
+// aborting the sweep. "toffoli" gates with more than
+// toffoliMCTControlsThreshold real control lines are priced via mctCost
+// instead of the flat table entry, since the underlying gate is then a
+// generalized MCT gate rather than an ordinary Toffoli.
+func (m GateCostModel) CalcQuantumCost(gates []circuit.Gate) (int, error) {
+	qc := 0
+	for _, gate := range gates {
+		cost, ok := m[gate.TypeName()]
+		if !ok {
+			return 0, fmt.Errorf("unknown gate type: %s", gate.TypeName())
+		}
+
+		if gate.TypeName() == "toffoli" {
+			if controls := circuit.CountControls(gate.ControlBits()); controls > toffoliMCTControlsThreshold {
+				cost = mctCost(controls, m["toffoli"])
+			}
+		}
+
+		qc += cost
+	}
+	return qc, nil
+}
+
+// gateFactoriesFor resolves the "gates" config names to the GateFactory
+// implementations the synthesizer should draw from. An empty names list
+// defaults to the original cnot+fredkin library. "toffoli" doubles as the
+// generalized MCT gate: the underlying factory places no limit on its
+// control bit count.
+func gateFactoriesFor(names []string) ([]circuit.GateFactory, error) {
+	if len(names) == 0 {
+		names = []string{"cnot", "fredkin"}
+	}
+
+	factories := make([]circuit.GateFactory, 0, len(names))
+	for _, name := range names {
+		switch name {
+		case "cnot":
+			factories = append(factories, circuit.NewCnotGateFactory())
+		case "fredkin":
+			factories = append(factories, circuit.NewFredkinGateFactory())
+		case "toffoli":
+			factories = append(factories, circuit.NewToffoliGateFactory())
+		default:
+			return nil, fmt.Errorf("unknown gate type: %s", name)
+		}
+	}
+	return factories, nil
+}
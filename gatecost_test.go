@@ -0,0 +1,111 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/Drofff/revsynth/circuit"
+)
+
+// stubGate is a minimal circuit.Gate used only to drive CalcQuantumCost in
+// tests, without depending on any real gate implementation.
+type stubGate struct {
+	typeName    string
+	controlBits []int
+}
+
+func (g stubGate) TypeName() string                               { return g.typeName }
+func (g stubGate) TargetBits() []int                              { return nil }
+func (g stubGate) ControlBits() []int                             { return g.controlBits }
+func (g stubGate) Apply(tt circuit.TruthTable) circuit.TruthTable { return tt }
+
+func TestCalcQuantumCostDefaults(t *testing.T) {
+	model := newGateCostModel(nil)
+
+	gates := []circuit.Gate{
+		stubGate{typeName: "cnot"},
+		stubGate{typeName: "toffoli", controlBits: []int{circuit.ControlBitPositive, circuit.ControlBitPositive}},
+	}
+	qc, err := model.CalcQuantumCost(gates)
+	if err != nil {
+		t.Fatalf("CalcQuantumCost: %v", err)
+	}
+	if qc != 1+5 {
+		t.Fatalf("CalcQuantumCost = %d, want %d", qc, 1+5)
+	}
+}
+
+func TestCalcQuantumCostAppliesOverrides(t *testing.T) {
+	model := newGateCostModel(map[string]int{"toffoli": 7})
+
+	gates := []circuit.Gate{
+		stubGate{typeName: "toffoli", controlBits: []int{circuit.ControlBitPositive, circuit.ControlBitPositive}},
+	}
+	qc, err := model.CalcQuantumCost(gates)
+	if err != nil {
+		t.Fatalf("CalcQuantumCost: %v", err)
+	}
+	if qc != 7 {
+		t.Fatalf("CalcQuantumCost = %d, want 7", qc)
+	}
+}
+
+func TestCalcQuantumCostUnknownGate(t *testing.T) {
+	model := newGateCostModel(nil)
+
+	if _, err := model.CalcQuantumCost([]circuit.Gate{stubGate{typeName: "mystery"}}); err == nil {
+		t.Fatalf("expected an error for an unknown gate type")
+	}
+}
+
+// TestCalcQuantumCostScalesMCTByControlCount exercises a "toffoli" gate
+// with more than 2 real control lines: in this circuit library a
+// "toffoli" factory has no control bits limit, so such a gate is a
+// generalized MCT gate and must be priced via mctCost, not the flat
+// table entry.
+func TestCalcQuantumCostScalesMCTByControlCount(t *testing.T) {
+	model := newGateCostModel(nil)
+
+	// 4 real controls, with one ignored line mixed in to make sure the
+	// count is taken from CountControls, not len(ControlBits()).
+	controlBits := []int{
+		circuit.ControlBitPositive,
+		circuit.ControlBitNegative,
+		circuit.ControlBitIgnore,
+		circuit.ControlBitPositive,
+		circuit.ControlBitPositive,
+	}
+	gates := []circuit.Gate{stubGate{typeName: "toffoli", controlBits: controlBits}}
+
+	qc, err := model.CalcQuantumCost(gates)
+	if err != nil {
+		t.Fatalf("CalcQuantumCost: %v", err)
+	}
+
+	want := mctCost(4, model["toffoli"])
+	if qc != want {
+		t.Fatalf("CalcQuantumCost = %d, want %d", qc, want)
+	}
+	if qc == model["toffoli"] {
+		t.Fatalf("CalcQuantumCost used the flat toffoli cost instead of scaling with control count")
+	}
+}
+
+// TestCalcQuantumCostMCTUsesOverriddenToffoliCost makes sure an
+// overridden "toffoli" cost feeds into mctCost too, not just plain
+// 2-control Toffolis.
+func TestCalcQuantumCostMCTUsesOverriddenToffoliCost(t *testing.T) {
+	model := newGateCostModel(map[string]int{"toffoli": 10})
+
+	controlBits := []int{circuit.ControlBitPositive, circuit.ControlBitPositive, circuit.ControlBitPositive}
+	gates := []circuit.Gate{stubGate{typeName: "toffoli", controlBits: controlBits}}
+
+	qc, err := model.CalcQuantumCost(gates)
+	if err != nil {
+		t.Fatalf("CalcQuantumCost: %v", err)
+	}
+
+	want := mctCost(3, 10)
+	if qc != want {
+		t.Fatalf("CalcQuantumCost = %d, want %d (mctCost should use the overridden toffoli cost)", qc, want)
+	}
+}
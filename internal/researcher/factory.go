@@ -0,0 +1,50 @@
+package researcher
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// StorageConfig carries the backend-specific settings a CreateRepository
+// call needs. Fields not relevant to the chosen backend are ignored.
+type StorageConfig struct {
+	// JSONLPath is the file CreateJSONLRepository appends to.
+	JSONLPath string
+	// SQLitePath is the database file CreateSQLiteRepository opens.
+	SQLitePath string
+	// S3Bucket/S3Prefix locate the objects CreateS3Repository reads/writes.
+	S3Bucket string
+	S3Prefix string
+	// DDBEndpoint overrides the DynamoDB endpoint, e.g. to point the ddb
+	// backend at DynamoDB Local for CI. Empty uses the SDK's default.
+	DDBEndpoint string
+	// DDBBatchSize buffers up to this many solutions before issuing a
+	// BatchWriteItem; 0 disables batching and falls back to one PutItem
+	// per solution.
+	DDBBatchSize int
+	// DDBFlushInterval bounds how long a partially-filled batch can sit
+	// before being flushed anyway.
+	DDBFlushInterval time.Duration
+}
+
+// CreateRepository builds the Repository implementation named by storage
+// ("ddb", "jsonl", "sqlite", or "s3"), replacing the old hardcoded
+// createDDBRepository call so a sweep can run without AWS credentials.
+func CreateRepository(ctx context.Context, storage string, cfg StorageConfig) (Repository, error) {
+	switch storage {
+	case "ddb":
+		if cfg.DDBBatchSize > 0 {
+			return CreateBatchingDDBRepository(ctx, cfg.DDBEndpoint, cfg.DDBBatchSize, cfg.DDBFlushInterval), nil
+		}
+		return CreateDDBRepository(ctx, cfg.DDBEndpoint), nil
+	case "jsonl":
+		return CreateJSONLRepository(cfg.JSONLPath)
+	case "sqlite":
+		return CreateSQLiteRepository(cfg.SQLitePath)
+	case "s3":
+		return CreateS3Repository(ctx, cfg.S3Bucket, cfg.S3Prefix)
+	default:
+		return nil, fmt.Errorf("unknown storage backend: %s", storage)
+	}
+}
@@ -0,0 +1,31 @@
+package researcher
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+func TestCreateRepositoryBackendSwitch(t *testing.T) {
+	dir := t.TempDir()
+
+	repo, err := CreateRepository(context.Background(), "jsonl", StorageConfig{JSONLPath: filepath.Join(dir, "solutions.jsonl")})
+	if err != nil {
+		t.Fatalf("jsonl: %v", err)
+	}
+	if _, ok := repo.(*jsonlRepository); !ok {
+		t.Fatalf("jsonl: got %T, want *jsonlRepository", repo)
+	}
+
+	repo, err = CreateRepository(context.Background(), "sqlite", StorageConfig{SQLitePath: filepath.Join(dir, "solutions.db")})
+	if err != nil {
+		t.Fatalf("sqlite: %v", err)
+	}
+	if _, ok := repo.(*sqliteRepository); !ok {
+		t.Fatalf("sqlite: got %T, want *sqliteRepository", repo)
+	}
+
+	if _, err := CreateRepository(context.Background(), "nonsense", StorageConfig{}); err == nil {
+		t.Fatalf("expected an error for an unknown storage backend")
+	}
+}
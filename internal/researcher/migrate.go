@@ -0,0 +1,29 @@
+package researcher
+
+import (
+	"context"
+	"fmt"
+)
+
+// Migrate reads every solution out of from and writes it into to, so data
+// collected against one backend (typically DynamoDB) can be exported for
+// offline analysis with another (typically jsonl or sqlite).
+func Migrate(ctx context.Context, from Repository, to Repository) (int, error) {
+	lister, ok := from.(Lister)
+	if !ok {
+		return 0, fmt.Errorf("source repository does not support listing solutions")
+	}
+
+	solutions, err := lister.ListSolutions(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, s := range solutions {
+		if err := to.SaveSolution(ctx, s); err != nil {
+			return i, err
+		}
+	}
+
+	return len(solutions), nil
+}
@@ -0,0 +1,74 @@
+package researcher
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/Drofff/revsynth/circuit"
+)
+
+// fakeLister is a minimal Repository+Lister backed by an in-memory slice,
+// standing in for a DynamoDB-backed source repository in Migrate tests.
+type fakeLister struct {
+	solutions []Solution
+}
+
+func (f *fakeLister) SaveSolution(ctx context.Context, s Solution) error {
+	f.solutions = append(f.solutions, s)
+	return nil
+}
+
+func (f *fakeLister) ListSolutions(ctx context.Context) ([]Solution, error) {
+	return f.solutions, nil
+}
+
+// TestMigrateFromDDBItemPreservesGateOrder exercises the ddb -> jsonl
+// migration path end to end: a solution is stored the way ddbRepository
+// stores it (gatesToStr), read back via fromItem the way ListSolutions
+// would, migrated into a jsonl backend, and read back again. The gate
+// order must match the original synthesis order at every hop.
+func TestMigrateFromDDBItemPreservesGateOrder(t *testing.T) {
+	want := []circuit.Gate{
+		gateDescriptor{typeName: "cnot", targetBits: []int{0}, controlBits: []int{1}},
+		gateDescriptor{typeName: "toffoli", targetBits: []int{2}, controlBits: []int{0, 1}},
+		gateDescriptor{typeName: "fredkin", targetBits: []int{1, 2}, controlBits: []int{0}},
+	}
+
+	item := solutionToItem("id-1", Solution{TargetVector: []int{0, 1}, Gates: want})
+
+	r := &ddbRepository{}
+	fromDDB, err := r.fromItem(item)
+	if err != nil {
+		t.Fatalf("fromItem: %v", err)
+	}
+	if !reflect.DeepEqual(fromDDB.Gates, want) {
+		t.Fatalf("fromItem gate order = %+v, want %+v", fromDDB.Gates, want)
+	}
+
+	src := &fakeLister{solutions: []Solution{fromDDB}}
+	dst, err := CreateJSONLRepository(filepath.Join(t.TempDir(), "solutions.jsonl"))
+	if err != nil {
+		t.Fatalf("CreateJSONLRepository: %v", err)
+	}
+
+	n, err := Migrate(context.Background(), src, dst)
+	if err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+	if n != 1 {
+		t.Fatalf("Migrate migrated %d solutions, want 1", n)
+	}
+
+	migrated, err := dst.(Lister).ListSolutions(context.Background())
+	if err != nil {
+		t.Fatalf("ListSolutions: %v", err)
+	}
+	if len(migrated) != 1 {
+		t.Fatalf("got %d migrated solutions, want 1", len(migrated))
+	}
+	if !reflect.DeepEqual(migrated[0].Gates, want) {
+		t.Fatalf("migrated gate order = %+v, want %+v", migrated[0].Gates, want)
+	}
+}
@@ -0,0 +1,226 @@
+// Package researcher holds the domain types and persistence layer shared
+// between the sweep runner (the repository root's main package) and the
+// tools that read its results back out, such as cmd/gqlserver.
+package researcher
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/Drofff/revsynth/circuit"
+)
+
+// Solution is a single synthesised circuit that met the sweep's quantum
+// cost target for a given truth vector.
+type Solution struct {
+	QuantumCost  int
+	TargetVector []int
+	Gates        []circuit.Gate
+
+	// ConfigHash identifies the AlgConfig the circuit was synthesised
+	// with, so a later sweep can tell whether a (targetVector, config)
+	// pair has already been covered.
+	ConfigHash string
+	// CircuitHash is a canonical hash of Gates, used to collapse
+	// equivalent circuits found from different random seeds into a
+	// single stored record.
+	CircuitHash string
+}
+
+func vectorToStr(v []int) string {
+	vss := make([]string, 0)
+	for _, el := range v {
+		vss = append(vss, strconv.Itoa(el))
+	}
+
+	return "[" + strings.Join(vss, ", ") + "]"
+}
+
+func parseVector(s string) ([]int, error) {
+	s = strings.TrimSpace(s)
+	s = strings.TrimPrefix(s, "[")
+	s = strings.TrimSuffix(s, "]")
+	if s == "" {
+		return []int{}, nil
+	}
+
+	parts := strings.Split(s, ", ")
+	v := make([]int, 0, len(parts))
+	for _, p := range parts {
+		el, err := strconv.Atoi(strings.TrimSpace(p))
+		if err != nil {
+			return nil, err
+		}
+		v = append(v, el)
+	}
+	return v, nil
+}
+
+func gatesToStr(gates []circuit.Gate) string {
+	gatesSS := make([]string, 0)
+
+	for i := len(gates) - 1; i >= 0; i-- {
+		gateS := gates[i].TypeName() + "(" + vectorToStr(gates[i].TargetBits()) + ", " + vectorToStr(gates[i].ControlBits()) + ")"
+		gatesSS = append(gatesSS, gateS)
+	}
+
+	return strings.Join(gatesSS, ", ")
+}
+
+// gateDescriptor is a typed stand-in for circuit.Gate, used to reconstruct
+// gates parsed back out of their stringified storage form. It carries no
+// synthesis behaviour, only the fields callers such as the GraphQL server
+// need to describe a gate.
+type gateDescriptor struct {
+	typeName    string
+	targetBits  []int
+	controlBits []int
+}
+
+func (g gateDescriptor) TypeName() string   { return g.typeName }
+func (g gateDescriptor) TargetBits() []int  { return g.targetBits }
+func (g gateDescriptor) ControlBits() []int { return g.controlBits }
+
+// Apply is a no-op: gateDescriptor only reconstructs gates already
+// synthesised and stored, for callers that describe them (GraphQL,
+// Migrate), never for re-running them through the ACO synthesiser, which
+// is the only caller that invokes Apply.
+func (g gateDescriptor) Apply(tt circuit.TruthTable) circuit.TruthTable { return tt }
+
+// parseGates is the inverse of gatesToStr. gatesToStr lays the sequence
+// out in reverse, so parseGates un-reverses it here, returning gates in
+// the same synthesis order they were passed to gatesToStr in.
+func parseGates(s string) ([]circuit.Gate, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return []circuit.Gate{}, nil
+	}
+
+	segments := splitGates(s)
+	gates := make([]circuit.Gate, len(segments))
+	for i, gateS := range segments {
+		open := strings.Index(gateS, "(")
+		typeName := strings.TrimSpace(gateS[:open])
+		args := strings.TrimSuffix(gateS[open+1:], ")")
+
+		vectors := strings.SplitN(args, "], ", 2)
+		if len(vectors) != 2 {
+			return nil, errInvalidGateFormat(gateS)
+		}
+
+		targetBits, err := parseVector(vectors[0] + "]")
+		if err != nil {
+			return nil, err
+		}
+		controlBits, err := parseVector(vectors[1])
+		if err != nil {
+			return nil, err
+		}
+
+		// segments is in gatesToStr's reversed order, so fill the
+		// result back-to-front to restore synthesis order.
+		gates[len(segments)-1-i] = gateDescriptor{
+			typeName:    typeName,
+			targetBits:  targetBits,
+			controlBits: controlBits,
+		}
+	}
+	return gates, nil
+}
+
+// splitGates splits a gatesToStr-joined sequence back into its individual
+// "type(...), type(...)" segments, respecting the nested brackets.
+func splitGates(s string) []string {
+	segments := make([]string, 0)
+	depth := 0
+	start := 0
+	for i, r := range s {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				segments = append(segments, strings.TrimSpace(s[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	segments = append(segments, strings.TrimSpace(s[start:]))
+	return segments
+}
+
+type errInvalidGateFormat string
+
+func (e errInvalidGateFormat) Error() string {
+	return "invalid gate format: " + string(e)
+}
+
+// PersistedGate is a JSON-friendly, fully-typed encoding of a circuit.Gate.
+// Backends that store structured documents (jsonl, sqlite, s3) keep gates
+// in this form instead of the lossy gatesToStr representation the original
+// DynamoDB-only repository used.
+type PersistedGate struct {
+	TypeName    string `json:"typeName"`
+	TargetBits  []int  `json:"targetBits"`
+	ControlBits []int  `json:"controlBits"`
+}
+
+// PersistedSolution is the on-disk/on-wire form of a Solution, with gates
+// encoded as PersistedGate rather than the circuit.Gate interface.
+type PersistedSolution struct {
+	ID           string          `json:"id"`
+	QuantumCost  int             `json:"quantumCost"`
+	TargetVector []int           `json:"targetVector"`
+	Gates        []PersistedGate `json:"gates"`
+	ConfigHash   string          `json:"configHash,omitempty"`
+	CircuitHash  string          `json:"circuitHash,omitempty"`
+}
+
+func toPersistedGates(gates []circuit.Gate) []PersistedGate {
+	pg := make([]PersistedGate, len(gates))
+	for i, g := range gates {
+		pg[i] = PersistedGate{
+			TypeName:    g.TypeName(),
+			TargetBits:  g.TargetBits(),
+			ControlBits: g.ControlBits(),
+		}
+	}
+	return pg
+}
+
+func fromPersistedGates(pg []PersistedGate) []circuit.Gate {
+	gates := make([]circuit.Gate, len(pg))
+	for i, g := range pg {
+		gates[i] = gateDescriptor{
+			typeName:    g.TypeName,
+			targetBits:  g.TargetBits,
+			controlBits: g.ControlBits,
+		}
+	}
+	return gates
+}
+
+// ToPersisted converts a Solution into its fully-typed, JSON-friendly form.
+func ToPersisted(id string, s Solution) PersistedSolution {
+	return PersistedSolution{
+		ID:           id,
+		QuantumCost:  s.QuantumCost,
+		TargetVector: s.TargetVector,
+		Gates:        toPersistedGates(s.Gates),
+		ConfigHash:   s.ConfigHash,
+		CircuitHash:  s.CircuitHash,
+	}
+}
+
+// FromPersisted is the inverse of ToPersisted.
+func FromPersisted(ps PersistedSolution) Solution {
+	return Solution{
+		QuantumCost:  ps.QuantumCost,
+		TargetVector: ps.TargetVector,
+		Gates:        fromPersistedGates(ps.Gates),
+		ConfigHash:   ps.ConfigHash,
+		CircuitHash:  ps.CircuitHash,
+	}
+}
@@ -0,0 +1,34 @@
+package researcher
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Drofff/revsynth/circuit"
+)
+
+func TestGatesToStrParseGatesRoundTrip(t *testing.T) {
+	gates := []circuit.Gate{
+		gateDescriptor{typeName: "cnot", targetBits: []int{0}, controlBits: []int{1}},
+		gateDescriptor{typeName: "toffoli", targetBits: []int{2}, controlBits: []int{0, 1}},
+		gateDescriptor{typeName: "fredkin", targetBits: []int{1, 2}, controlBits: []int{}},
+	}
+
+	got, err := parseGates(gatesToStr(gates))
+	if err != nil {
+		t.Fatalf("parseGates: %v", err)
+	}
+	if !reflect.DeepEqual(got, gates) {
+		t.Fatalf("round-tripped gates = %+v, want %+v", got, gates)
+	}
+}
+
+func TestParseGatesEmpty(t *testing.T) {
+	got, err := parseGates("")
+	if err != nil {
+		t.Fatalf("parseGates: %v", err)
+	}
+	if len(got) != 0 {
+		t.Fatalf("parseGates(\"\") = %+v, want empty", got)
+	}
+}
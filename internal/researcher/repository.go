@@ -0,0 +1,270 @@
+package researcher
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconf "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	awstypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/google/uuid"
+)
+
+const (
+	dynamoDBTableName    = "revsynth-research-results"
+	dynamoDBPartitionKey = "id"
+	dynamoDBSortKey      = "truthVector"
+	dynamoDBQCKey        = "quantumCost"
+	dynamoDBGatesKey     = "gates"
+)
+
+// Repository persists and retrieves synthesised solutions.
+type Repository interface {
+	SaveSolution(ctx context.Context, s Solution) error
+}
+
+// Queryable is implemented by repositories that can serve the lookups the
+// GraphQL server exposes. Not every Repository has to support it.
+type Queryable interface {
+	GetSolutionByID(ctx context.Context, id string) (Solution, error)
+	QuerySolutions(ctx context.Context, minQC, maxQC int) ([]Solution, error)
+	GetSolutionsByTargetVector(ctx context.Context, targetVector []int) ([]Solution, error)
+}
+
+// Lister is implemented by repositories that can enumerate every solution
+// they hold, which Migrate relies on to move data between backends.
+type Lister interface {
+	ListSolutions(ctx context.Context) ([]Solution, error)
+}
+
+// Closer is implemented by repositories that buffer writes in memory and
+// need an explicit flush before the process exits, such as
+// batchingDDBRepository. Callers should invoke Close on a clean shutdown
+// so nothing still sitting in a buffer is lost.
+type Closer interface {
+	Close(ctx context.Context) error
+}
+
+type ddbRepository struct {
+	ddbClient *dynamodb.Client
+}
+
+func (r *ddbRepository) SaveSolution(ctx context.Context, s Solution) error {
+	_, err := r.ddbClient.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(dynamoDBTableName),
+		Item:      solutionToItem(uuid.NewString(), s),
+	})
+	return err
+}
+
+// solutionToItem builds the DynamoDB item for a solution, shared by
+// ddbRepository and batchingDDBRepository.
+func solutionToItem(id string, s Solution) map[string]awstypes.AttributeValue {
+	vectorS := vectorToStr(s.TargetVector)
+	gatesS := gatesToStr(s.Gates)
+
+	item := map[string]awstypes.AttributeValue{
+		dynamoDBPartitionKey: &awstypes.AttributeValueMemberS{Value: id},
+		dynamoDBSortKey:      &awstypes.AttributeValueMemberS{Value: vectorS},
+		dynamoDBQCKey:        &awstypes.AttributeValueMemberN{Value: strconv.Itoa(s.QuantumCost)},
+		dynamoDBGatesKey:     &awstypes.AttributeValueMemberS{Value: gatesS},
+	}
+	if s.ConfigHash != "" {
+		item[dynamoDBConfigHashKey] = &awstypes.AttributeValueMemberS{Value: s.ConfigHash}
+	}
+	if s.CircuitHash != "" {
+		item[dynamoDBCircuitHashKey] = &awstypes.AttributeValueMemberS{Value: s.CircuitHash}
+	}
+	return item
+}
+
+func (r *ddbRepository) fromItem(item map[string]awstypes.AttributeValue) (Solution, error) {
+	vectorS, ok := item[dynamoDBSortKey].(*awstypes.AttributeValueMemberS)
+	if !ok {
+		return Solution{}, fmt.Errorf("invalid item: missing %s", dynamoDBSortKey)
+	}
+	targetVector, err := parseVector(vectorS.Value)
+	if err != nil {
+		return Solution{}, err
+	}
+
+	qcN, ok := item[dynamoDBQCKey].(*awstypes.AttributeValueMemberN)
+	if !ok {
+		return Solution{}, fmt.Errorf("invalid item: missing %s", dynamoDBQCKey)
+	}
+	qc, err := strconv.Atoi(qcN.Value)
+	if err != nil {
+		return Solution{}, err
+	}
+
+	gatesS, ok := item[dynamoDBGatesKey].(*awstypes.AttributeValueMemberS)
+	if !ok {
+		return Solution{}, fmt.Errorf("invalid item: missing %s", dynamoDBGatesKey)
+	}
+	gates, err := parseGates(gatesS.Value)
+	if err != nil {
+		return Solution{}, err
+	}
+
+	s := Solution{QuantumCost: qc, TargetVector: targetVector, Gates: gates}
+	if configHashS, ok := item[dynamoDBConfigHashKey].(*awstypes.AttributeValueMemberS); ok {
+		s.ConfigHash = configHashS.Value
+	}
+	if circuitHashS, ok := item[dynamoDBCircuitHashKey].(*awstypes.AttributeValueMemberS); ok {
+		s.CircuitHash = circuitHashS.Value
+	}
+	return s, nil
+}
+
+func (r *ddbRepository) GetSolutionByID(ctx context.Context, id string) (Solution, error) {
+	// The table's primary key is (id, truthVector), so a lookup by id alone
+	// is a Query on the partition key rather than a GetItem.
+	keyCond := expression.Key(dynamoDBPartitionKey).Equal(expression.Value(id))
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCond).Build()
+	if err != nil {
+		return Solution{}, err
+	}
+
+	out, err := r.ddbClient.Query(ctx, &dynamodb.QueryInput{
+		TableName:                 aws.String(dynamoDBTableName),
+		KeyConditionExpression:    expr.KeyCondition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	})
+	if err != nil {
+		return Solution{}, err
+	}
+	if len(out.Items) == 0 {
+		return Solution{}, fmt.Errorf("no solution with id %s", id)
+	}
+	return r.fromItem(out.Items[0])
+}
+
+// QuerySolutions scans the table for solutions whose quantumCost falls
+// within [minQC, maxQC]. Either bound may be left at 0/MaxInt to leave it
+// unconstrained.
+func (r *ddbRepository) QuerySolutions(ctx context.Context, minQC, maxQC int) ([]Solution, error) {
+	filter := expression.Name(dynamoDBQCKey).Between(expression.Value(minQC), expression.Value(maxQC))
+	expr, err := expression.NewBuilder().WithFilter(filter).Build()
+	if err != nil {
+		return nil, err
+	}
+
+	input := &dynamodb.ScanInput{
+		TableName:                 aws.String(dynamoDBTableName),
+		FilterExpression:          expr.Filter(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	}
+	items := make([]map[string]awstypes.AttributeValue, 0)
+	for {
+		out, err := r.ddbClient.Scan(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, out.Items...)
+		if len(out.LastEvaluatedKey) == 0 {
+			break
+		}
+		input.ExclusiveStartKey = out.LastEvaluatedKey
+	}
+
+	return r.itemsToSolutions(items)
+}
+
+// GetSolutionsByTargetVector queries the table's sort key for every
+// solution recorded against the given truth vector.
+func (r *ddbRepository) GetSolutionsByTargetVector(ctx context.Context, targetVector []int) ([]Solution, error) {
+	keyCond := expression.Key(dynamoDBSortKey).Equal(expression.Value(vectorToStr(targetVector)))
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCond).Build()
+	if err != nil {
+		return nil, err
+	}
+
+	input := &dynamodb.QueryInput{
+		TableName:                 aws.String(dynamoDBTableName),
+		IndexName:                 aws.String(dynamoDBSortKey + "-index"),
+		KeyConditionExpression:    expr.KeyCondition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	}
+	items := make([]map[string]awstypes.AttributeValue, 0)
+	for {
+		out, err := r.ddbClient.Query(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, out.Items...)
+		if len(out.LastEvaluatedKey) == 0 {
+			break
+		}
+		input.ExclusiveStartKey = out.LastEvaluatedKey
+	}
+
+	return r.itemsToSolutions(items)
+}
+
+func (r *ddbRepository) itemsToSolutions(items []map[string]awstypes.AttributeValue) ([]Solution, error) {
+	solutions := make([]Solution, 0, len(items))
+	for _, item := range items {
+		if sortS, ok := item[dynamoDBSortKey].(*awstypes.AttributeValueMemberS); ok && sortS.Value == sweepProgressSortValue {
+			continue
+		}
+
+		s, err := r.fromItem(item)
+		if err != nil {
+			return nil, err
+		}
+		solutions = append(solutions, s)
+	}
+	return solutions, nil
+}
+
+// ListSolutions scans the whole table. It exists so Migrate can export
+// DynamoDB-stored solutions into another backend.
+func (r *ddbRepository) ListSolutions(ctx context.Context) ([]Solution, error) {
+	input := &dynamodb.ScanInput{
+		TableName: aws.String(dynamoDBTableName),
+	}
+	items := make([]map[string]awstypes.AttributeValue, 0)
+	for {
+		out, err := r.ddbClient.Scan(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, out.Items...)
+		if len(out.LastEvaluatedKey) == 0 {
+			break
+		}
+		input.ExclusiveStartKey = out.LastEvaluatedKey
+	}
+	return r.itemsToSolutions(items)
+}
+
+// CreateDDBRepository builds a Repository backed by DynamoDB, using
+// whatever AWS credentials/region are configured in the environment. An
+// empty endpoint uses the SDK's default resolution; a non-empty one
+// (typically DynamoDB Local for CI) overrides it.
+func CreateDDBRepository(ctx context.Context, endpoint string) Repository {
+	return &ddbRepository{ddbClient: newDDBClient(ctx, endpoint)}
+}
+
+func newDDBClient(ctx context.Context, endpoint string) *dynamodb.Client {
+	conf, err := awsconf.LoadDefaultConfig(ctx, func(opts *awsconf.LoadOptions) error {
+		opts.Region = "us-east-1"
+		return nil
+	})
+	if err != nil {
+		log.Fatalln("Configure AWS:", err)
+	}
+
+	return dynamodb.NewFromConfig(conf, func(o *dynamodb.Options) {
+		if endpoint != "" {
+			o.BaseEndpoint = aws.String(endpoint)
+		}
+	})
+}
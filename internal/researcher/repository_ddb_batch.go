@@ -0,0 +1,184 @@
+package researcher
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	awstypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/google/uuid"
+)
+
+// ddbBatchWriteLimit is the maximum number of items a single
+// BatchWriteItem call accepts.
+const ddbBatchWriteLimit = 25
+
+// DynamoDBAPI is the subset of the DynamoDB client batchingDDBRepository
+// writes through, modeled on aws-dax-go's DynamoDBAPI. Only the batch
+// writes go through it: reads (Queryable, Lister) use the embedded
+// ddbRepository's concrete *dynamodb.Client, and
+// CreateBatchingDDBRepository always sets api to a plain DynamoDB client.
+type DynamoDBAPI interface {
+	PutItem(ctx context.Context, in *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error)
+	BatchWriteItem(ctx context.Context, in *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error)
+}
+
+// batchingDDBRepository buffers solutions and flushes them to DynamoDB via
+// BatchWriteItem, instead of issuing one PutItem per solution. It embeds a
+// *ddbRepository so reads (Queryable, Lister) keep working unchanged.
+type batchingDDBRepository struct {
+	*ddbRepository
+	api DynamoDBAPI
+
+	batchSize  int
+	flushEvery time.Duration
+
+	mu      sync.Mutex
+	pending []awstypes.WriteRequest
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// CreateBatchingDDBRepository wraps a DynamoDB-backed Repository with
+// write batching: up to batchSize solutions are buffered and flushed
+// together, or sooner if flushEvery elapses first. A non-empty endpoint
+// points the client at DynamoDB Local for CI.
+func CreateBatchingDDBRepository(ctx context.Context, endpoint string, batchSize int, flushEvery time.Duration) Repository {
+	if batchSize <= 0 || batchSize > ddbBatchWriteLimit {
+		batchSize = ddbBatchWriteLimit
+	}
+	if flushEvery <= 0 {
+		flushEvery = 5 * time.Second
+	}
+
+	client := newDDBClient(ctx, endpoint)
+	r := &batchingDDBRepository{
+		ddbRepository: &ddbRepository{ddbClient: client},
+		api:           client,
+		batchSize:     batchSize,
+		flushEvery:    flushEvery,
+		stop:          make(chan struct{}),
+		done:          make(chan struct{}),
+	}
+
+	go r.flushLoop(ctx)
+	return r
+}
+
+func (r *batchingDDBRepository) flushLoop(ctx context.Context) {
+	defer close(r.done)
+
+	ticker := time.NewTicker(r.flushEvery)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := r.Flush(ctx); err != nil {
+				log.Println("Periodic flush failed:", err)
+			}
+		case <-r.stop:
+			return
+		}
+	}
+}
+
+func (r *batchingDDBRepository) SaveSolution(ctx context.Context, s Solution) error {
+	r.mu.Lock()
+	r.pending = append(r.pending, awstypes.WriteRequest{
+		PutRequest: &awstypes.PutRequest{Item: solutionToItem(uuid.NewString(), s)},
+	})
+	full := len(r.pending) >= r.batchSize
+	r.mu.Unlock()
+
+	if full {
+		return r.Flush(ctx)
+	}
+	return nil
+}
+
+// Flush writes out any buffered solutions immediately.
+func (r *batchingDDBRepository) Flush(ctx context.Context) error {
+	r.mu.Lock()
+	batch := r.pending
+	r.pending = nil
+	r.mu.Unlock()
+
+	for len(batch) > 0 {
+		n := ddbBatchWriteLimit
+		if n > len(batch) {
+			n = len(batch)
+		}
+
+		if err := r.writeBatchWithRetry(ctx, batch[:n]); err != nil {
+			return err
+		}
+		batch = batch[n:]
+	}
+	return nil
+}
+
+// writeBatchWithRetry issues BatchWriteItem and retries any
+// UnprocessedItems with exponential backoff, as the SDK docs recommend.
+func (r *batchingDDBRepository) writeBatchWithRetry(ctx context.Context, requests []awstypes.WriteRequest) error {
+	backoff := 50 * time.Millisecond
+	for attempt := 0; attempt < 5 && len(requests) > 0; attempt++ {
+		out, err := r.api.BatchWriteItem(ctx, &dynamodb.BatchWriteItemInput{
+			RequestItems: map[string][]awstypes.WriteRequest{dynamoDBTableName: requests},
+		})
+		if err != nil {
+			return err
+		}
+
+		requests = out.UnprocessedItems[dynamoDBTableName]
+		if len(requests) == 0 {
+			return nil
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		backoff *= 2
+	}
+
+	if len(requests) > 0 {
+		return errors.New("gave up retrying unprocessed DynamoDB batch writes")
+	}
+	return nil
+}
+
+// Close stops the periodic flush loop and writes out anything still
+// buffered.
+func (r *batchingDDBRepository) Close(ctx context.Context) error {
+	close(r.stop)
+	<-r.done
+	return r.Flush(ctx)
+}
+
+// HasCoverage overrides ddbRepository's, which only sees what's already
+// in the table: a solution still sitting in r.pending because the batch
+// hasn't flushed would otherwise look uncovered and be resynthesised.
+// Flushing first makes the check see everything written so far.
+func (r *batchingDDBRepository) HasCoverage(ctx context.Context, targetVector []int, configHash string, maxQC int) (bool, error) {
+	if err := r.Flush(ctx); err != nil {
+		return false, err
+	}
+	return r.ddbRepository.HasCoverage(ctx, targetVector, configHash, maxQC)
+}
+
+// HasCircuit overrides ddbRepository's for the same reason as
+// HasCoverage: without a forced flush, two circuits synthesised within
+// the same batch window could both pass the dedup check and both get
+// buffered, defeating it.
+func (r *batchingDDBRepository) HasCircuit(ctx context.Context, circuitHash string) (bool, error) {
+	if err := r.Flush(ctx); err != nil {
+		return false, err
+	}
+	return r.ddbRepository.HasCircuit(ctx, circuitHash)
+}
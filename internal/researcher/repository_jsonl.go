@@ -0,0 +1,72 @@
+package researcher
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// jsonlRepository appends one JSON-encoded PersistedSolution per line to a
+// local file. It needs no credentials, which makes it the default choice
+// for researchers who just want to run a sweep on a laptop.
+type jsonlRepository struct {
+	mu   sync.Mutex
+	path string
+}
+
+// CreateJSONLRepository opens (creating if necessary) an append-only
+// .jsonl file at path for storing solutions.
+func CreateJSONLRepository(path string) (Repository, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	f.Close()
+	return &jsonlRepository{path: path}, nil
+}
+
+func (r *jsonlRepository) SaveSolution(ctx context.Context, s Solution) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	f, err := os.OpenFile(r.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	line, err := json.Marshal(ToPersisted(uuid.NewString(), s))
+	if err != nil {
+		return err
+	}
+
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
+
+// ListSolutions reads every solution stored in the file, in append order.
+func (r *jsonlRepository) ListSolutions(ctx context.Context) ([]Solution, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	f, err := os.Open(r.path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	solutions := make([]Solution, 0)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var ps PersistedSolution
+		if err := json.Unmarshal(scanner.Bytes(), &ps); err != nil {
+			return nil, err
+		}
+		solutions = append(solutions, FromPersisted(ps))
+	}
+	return solutions, scanner.Err()
+}
@@ -0,0 +1,39 @@
+package researcher
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestJSONLRepositoryAppendAndReadRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "solutions.jsonl")
+	repo, err := CreateJSONLRepository(path)
+	if err != nil {
+		t.Fatalf("CreateJSONLRepository: %v", err)
+	}
+
+	want := []Solution{
+		{QuantumCost: 5, TargetVector: []int{0, 1}},
+		{QuantumCost: 9, TargetVector: []int{1, 0}},
+	}
+	for _, s := range want {
+		if err := repo.SaveSolution(context.Background(), s); err != nil {
+			t.Fatalf("SaveSolution: %v", err)
+		}
+	}
+
+	got, err := repo.(Lister).ListSolutions(context.Background())
+	if err != nil {
+		t.Fatalf("ListSolutions: %v", err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d solutions, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i].QuantumCost != want[i].QuantumCost || !reflect.DeepEqual(got[i].TargetVector, want[i].TargetVector) {
+			t.Fatalf("solution %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
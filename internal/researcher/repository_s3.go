@@ -0,0 +1,100 @@
+package researcher
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconf "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/google/uuid"
+)
+
+// s3Repository stores one JSON object per solution under a prefix, e.g.
+// s3://bucket/prefix/<uuid>.json.
+type s3Repository struct {
+	client *s3.Client
+	bucket string
+	prefix string
+}
+
+// CreateS3Repository builds a Repository backed by S3, storing each
+// solution as its own object under bucket/prefix.
+func CreateS3Repository(ctx context.Context, bucket, prefix string) (Repository, error) {
+	conf, err := awsconf.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &s3Repository{
+		client: s3.NewFromConfig(conf),
+		bucket: bucket,
+		prefix: prefix,
+	}, nil
+}
+
+func (r *s3Repository) key(id string) string {
+	if r.prefix == "" {
+		return id + ".json"
+	}
+	return r.prefix + "/" + id + ".json"
+}
+
+func (r *s3Repository) SaveSolution(ctx context.Context, s Solution) error {
+	id := uuid.NewString()
+	body, err := json.Marshal(ToPersisted(id, s))
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket: &r.bucket,
+		Key:    aws.String(r.key(id)),
+		Body:   bytes.NewReader(body),
+	})
+	return err
+}
+
+// ListSolutions lists and fetches every object under the repository's
+// prefix, decoding each as a PersistedSolution.
+func (r *s3Repository) ListSolutions(ctx context.Context) ([]Solution, error) {
+	solutions := make([]Solution, 0)
+
+	paginator := s3.NewListObjectsV2Paginator(r.client, &s3.ListObjectsV2Input{
+		Bucket: &r.bucket,
+		Prefix: aws.String(r.prefix),
+	})
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, obj := range page.Contents {
+			out, err := r.client.GetObject(ctx, &s3.GetObjectInput{
+				Bucket: &r.bucket,
+				Key:    obj.Key,
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			body, err := io.ReadAll(out.Body)
+			out.Body.Close()
+			if err != nil {
+				return nil, err
+			}
+
+			var ps PersistedSolution
+			if err := json.Unmarshal(body, &ps); err != nil {
+				return nil, err
+			}
+			solutions = append(solutions, FromPersisted(ps))
+		}
+	}
+
+	return solutions, nil
+}
@@ -0,0 +1,144 @@
+package researcher
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/google/uuid"
+	_ "modernc.org/sqlite"
+)
+
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS solutions (
+	id TEXT PRIMARY KEY,
+	quantum_cost INTEGER NOT NULL,
+	target_vector TEXT NOT NULL,
+	gates TEXT NOT NULL,
+	config_hash TEXT NOT NULL DEFAULT '',
+	circuit_hash TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE IF NOT EXISTS sweep_progress (
+	input_hash TEXT PRIMARY KEY,
+	config_index INTEGER NOT NULL
+);
+`
+
+type sqliteRepository struct {
+	db *sql.DB
+}
+
+// CreateSQLiteRepository opens (creating if necessary) a SQLite database at
+// path, with a solutions table ready to receive sweep results.
+func CreateSQLiteRepository(path string) (Repository, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &sqliteRepository{db: db}, nil
+}
+
+func (r *sqliteRepository) SaveSolution(ctx context.Context, s Solution) error {
+	targetVectorJSON, err := json.Marshal(s.TargetVector)
+	if err != nil {
+		return err
+	}
+	gatesJSON, err := json.Marshal(toPersistedGates(s.Gates))
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.ExecContext(ctx,
+		`INSERT INTO solutions (id, quantum_cost, target_vector, gates, config_hash, circuit_hash) VALUES (?, ?, ?, ?, ?, ?)`,
+		uuid.NewString(), s.QuantumCost, string(targetVectorJSON), string(gatesJSON), s.ConfigHash, s.CircuitHash)
+	return err
+}
+
+// ListSolutions returns every solution in the database.
+func (r *sqliteRepository) ListSolutions(ctx context.Context) ([]Solution, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT quantum_cost, target_vector, gates, config_hash, circuit_hash FROM solutions`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	solutions := make([]Solution, 0)
+	for rows.Next() {
+		var qc int
+		var targetVectorJSON, gatesJSON, configHash, circuitHash string
+		if err := rows.Scan(&qc, &targetVectorJSON, &gatesJSON, &configHash, &circuitHash); err != nil {
+			return nil, err
+		}
+
+		var targetVector []int
+		if err := json.Unmarshal([]byte(targetVectorJSON), &targetVector); err != nil {
+			return nil, err
+		}
+		var gates []PersistedGate
+		if err := json.Unmarshal([]byte(gatesJSON), &gates); err != nil {
+			return nil, err
+		}
+
+		solutions = append(solutions, Solution{
+			QuantumCost:  qc,
+			TargetVector: targetVector,
+			Gates:        fromPersistedGates(gates),
+			ConfigHash:   configHash,
+			CircuitHash:  circuitHash,
+		})
+	}
+	return solutions, rows.Err()
+}
+
+// HasCircuit reports whether circuitHash has already been recorded.
+func (r *sqliteRepository) HasCircuit(ctx context.Context, circuitHash string) (bool, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(1) FROM solutions WHERE circuit_hash = ?`, circuitHash).Scan(&count)
+	return count > 0, err
+}
+
+// HasCoverage reports whether a solution already exists for
+// targetVector/configHash with quantum cost at or below maxQC.
+func (r *sqliteRepository) HasCoverage(ctx context.Context, targetVector []int, configHash string, maxQC int) (bool, error) {
+	targetVectorJSON, err := json.Marshal(targetVector)
+	if err != nil {
+		return false, err
+	}
+
+	var count int
+	err = r.db.QueryRowContext(ctx,
+		`SELECT COUNT(1) FROM solutions WHERE target_vector = ? AND config_hash = ? AND quantum_cost <= ?`,
+		string(targetVectorJSON), configHash, maxQC).Scan(&count)
+	return count > 0, err
+}
+
+// SaveProgress records the last sweep config fully processed for
+// inputHash, so a restart can resume from there.
+func (r *sqliteRepository) SaveProgress(ctx context.Context, inputHash string, configIndex int) error {
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO sweep_progress (input_hash, config_index) VALUES (?, ?)
+		 ON CONFLICT(input_hash) DO UPDATE SET config_index = excluded.config_index`,
+		inputHash, configIndex)
+	return err
+}
+
+// LoadProgress returns the last config index saved for inputHash.
+func (r *sqliteRepository) LoadProgress(ctx context.Context, inputHash string) (int, bool, error) {
+	var configIndex int
+	err := r.db.QueryRowContext(ctx,
+		`SELECT config_index FROM sweep_progress WHERE input_hash = ?`, inputHash).Scan(&configIndex)
+	if err == sql.ErrNoRows {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return configIndex, true, nil
+}
@@ -0,0 +1,125 @@
+package researcher
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	awstypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+const (
+	dynamoDBConfigHashKey  = "configHash"
+	dynamoDBCircuitHashKey = "circuitHash"
+
+	// sweepProgressSortValue is the sentinel sort-key value used for the
+	// one progress item per input file, stored in the same table as
+	// solutions so no extra table is needed.
+	sweepProgressSortValue = "__sweep_progress__"
+	dynamoDBConfigIndexKey = "configIndex"
+)
+
+// HasCoverage queries the table's truthVector index for a solution that
+// already satisfies (targetVector, configHash) at or below maxQC.
+func (r *ddbRepository) HasCoverage(ctx context.Context, targetVector []int, configHash string, maxQC int) (bool, error) {
+	keyCond := expression.Key(dynamoDBSortKey).Equal(expression.Value(vectorToStr(targetVector)))
+	filter := expression.Name(dynamoDBConfigHashKey).Equal(expression.Value(configHash)).
+		And(expression.Name(dynamoDBQCKey).LessThanEqual(expression.Value(maxQC)))
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCond).WithFilter(filter).Build()
+	if err != nil {
+		return false, err
+	}
+
+	input := &dynamodb.QueryInput{
+		TableName:                 aws.String(dynamoDBTableName),
+		IndexName:                 aws.String(dynamoDBSortKey + "-index"),
+		KeyConditionExpression:    expr.KeyCondition(),
+		FilterExpression:          expr.Filter(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	}
+	for {
+		out, err := r.ddbClient.Query(ctx, input)
+		if err != nil {
+			return false, err
+		}
+		if len(out.Items) > 0 {
+			return true, nil
+		}
+		if len(out.LastEvaluatedKey) == 0 {
+			return false, nil
+		}
+		input.ExclusiveStartKey = out.LastEvaluatedKey
+	}
+}
+
+// HasCircuit scans for a solution already recorded under circuitHash. A
+// scan is acceptable here: it only runs once per synthesised circuit, not
+// once per ant/iteration inside the ACO search.
+func (r *ddbRepository) HasCircuit(ctx context.Context, circuitHash string) (bool, error) {
+	filter := expression.Name(dynamoDBCircuitHashKey).Equal(expression.Value(circuitHash))
+	expr, err := expression.NewBuilder().WithFilter(filter).Build()
+	if err != nil {
+		return false, err
+	}
+
+	input := &dynamodb.ScanInput{
+		TableName:                 aws.String(dynamoDBTableName),
+		FilterExpression:          expr.Filter(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	}
+	for {
+		out, err := r.ddbClient.Scan(ctx, input)
+		if err != nil {
+			return false, err
+		}
+		if len(out.Items) > 0 {
+			return true, nil
+		}
+		if len(out.LastEvaluatedKey) == 0 {
+			return false, nil
+		}
+		input.ExclusiveStartKey = out.LastEvaluatedKey
+	}
+}
+
+func (r *ddbRepository) SaveProgress(ctx context.Context, inputHash string, configIndex int) error {
+	_, err := r.ddbClient.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(dynamoDBTableName),
+		Item: map[string]awstypes.AttributeValue{
+			dynamoDBPartitionKey:   &awstypes.AttributeValueMemberS{Value: "sweep_progress#" + inputHash},
+			dynamoDBSortKey:        &awstypes.AttributeValueMemberS{Value: sweepProgressSortValue},
+			dynamoDBConfigIndexKey: &awstypes.AttributeValueMemberN{Value: strconv.Itoa(configIndex)},
+		},
+	})
+	return err
+}
+
+func (r *ddbRepository) LoadProgress(ctx context.Context, inputHash string) (int, bool, error) {
+	out, err := r.ddbClient.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(dynamoDBTableName),
+		Key: map[string]awstypes.AttributeValue{
+			dynamoDBPartitionKey: &awstypes.AttributeValueMemberS{Value: "sweep_progress#" + inputHash},
+			dynamoDBSortKey:      &awstypes.AttributeValueMemberS{Value: sweepProgressSortValue},
+		},
+	})
+	if err != nil {
+		return 0, false, err
+	}
+	if out.Item == nil {
+		return 0, false, nil
+	}
+
+	idxN, ok := out.Item[dynamoDBConfigIndexKey].(*awstypes.AttributeValueMemberN)
+	if !ok {
+		return 0, false, nil
+	}
+	idx, err := strconv.Atoi(idxN.Value)
+	if err != nil {
+		return 0, false, err
+	}
+	return idx, true, nil
+}
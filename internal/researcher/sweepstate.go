@@ -0,0 +1,70 @@
+package researcher
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/Drofff/revsynth/circuit"
+)
+
+// CanonicalCircuitHash hashes a gate sequence in a form that is stable
+// across equivalent circuits found from different random seeds: each
+// gate's target bits are sorted before hashing (TargetBits is a sparse
+// list of line indices a multi-target gate like Fredkin applies to
+// symmetrically, so line order doesn't change the gate), so two circuits
+// that differ only in target bit order hash identically. ControlBits is
+// NOT sorted: per circuit.Gate's doc comment it's a dense, positional
+// array where the index is the circuit line and the value is that
+// line's control mode, so reordering it reassigns control modes to
+// different lines and would change the gate, not canonicalize it.
+func CanonicalCircuitHash(gates []circuit.Gate) string {
+	parts := make([]string, len(gates))
+	for i, g := range gates {
+		parts[i] = g.TypeName() + "/" + sortedVectorKey(g.TargetBits()) + "/" + vectorToStr(g.ControlBits())
+	}
+
+	h := sha256.Sum256([]byte(strings.Join(parts, "|")))
+	return hex.EncodeToString(h[:])
+}
+
+func sortedVectorKey(v []int) string {
+	sorted := append([]int(nil), v...)
+	sort.Ints(sorted)
+	return vectorToStr(sorted)
+}
+
+// ConfigHash hashes an arbitrary, JSON-marshalable sweep configuration
+// (typically an AlgConfig) so solutions can be grouped by the config that
+// produced them without the caller needing to know its shape.
+func ConfigHash(config interface{}) (string, error) {
+	b, err := json.Marshal(config)
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.Sum256(b)
+	return hex.EncodeToString(h[:]), nil
+}
+
+// SweepState is implemented by repositories that can support resumable
+// sweeps: checking whether a (targetVector, configHash) pair is already
+// covered, detecting circuits that duplicate one already stored, and
+// persisting/restoring how far a sweep got through its config list.
+type SweepState interface {
+	// HasCoverage reports whether a solution already exists for
+	// targetVector/configHash with quantum cost at or below maxQC.
+	HasCoverage(ctx context.Context, targetVector []int, configHash string, maxQC int) (bool, error)
+	// HasCircuit reports whether circuitHash has already been recorded,
+	// regardless of which config produced it.
+	HasCircuit(ctx context.Context, circuitHash string) (bool, error)
+	// SaveProgress records that configIndex is the last sweep config
+	// fully processed for the input identified by inputHash.
+	SaveProgress(ctx context.Context, inputHash string, configIndex int) error
+	// LoadProgress returns the last config index saved for inputHash, or
+	// ok=false if no progress has been recorded yet.
+	LoadProgress(ctx context.Context, inputHash string) (configIndex int, ok bool, err error)
+}
@@ -0,0 +1,51 @@
+package researcher
+
+import (
+	"testing"
+
+	"github.com/Drofff/revsynth/circuit"
+)
+
+func TestCanonicalCircuitHashStableAcrossTargetBitOrder(t *testing.T) {
+	// Fredkin swaps its two target lines symmetrically, so reordering
+	// TargetBits describes the same gate.
+	a := []circuit.Gate{
+		gateDescriptor{typeName: "fredkin", targetBits: []int{1, 2}, controlBits: []int{circuit.ControlBitPositive, circuit.ControlBitIgnore, circuit.ControlBitIgnore}},
+	}
+	b := []circuit.Gate{
+		gateDescriptor{typeName: "fredkin", targetBits: []int{2, 1}, controlBits: []int{circuit.ControlBitPositive, circuit.ControlBitIgnore, circuit.ControlBitIgnore}},
+	}
+
+	if CanonicalCircuitHash(a) != CanonicalCircuitHash(b) {
+		t.Fatalf("hashes differ for circuits that only differ in target bit order")
+	}
+}
+
+func TestCanonicalCircuitHashDiffersForDifferentControlBitOrder(t *testing.T) {
+	// ControlBits is positional (index = circuit line), so reassigning
+	// which line is positive vs. negative is a different gate and must
+	// not hash the same.
+	a := []circuit.Gate{
+		gateDescriptor{typeName: "toffoli", targetBits: []int{2}, controlBits: []int{circuit.ControlBitPositive, circuit.ControlBitNegative}},
+	}
+	b := []circuit.Gate{
+		gateDescriptor{typeName: "toffoli", targetBits: []int{2}, controlBits: []int{circuit.ControlBitNegative, circuit.ControlBitPositive}},
+	}
+
+	if CanonicalCircuitHash(a) == CanonicalCircuitHash(b) {
+		t.Fatalf("hashes match for circuits with different control bit assignments")
+	}
+}
+
+func TestCanonicalCircuitHashDiffersForDifferentGates(t *testing.T) {
+	a := []circuit.Gate{
+		gateDescriptor{typeName: "cnot", targetBits: []int{0}, controlBits: []int{1}},
+	}
+	b := []circuit.Gate{
+		gateDescriptor{typeName: "cnot", targetBits: []int{1}, controlBits: []int{0}},
+	}
+
+	if CanonicalCircuitHash(a) == CanonicalCircuitHash(b) {
+		t.Fatalf("hashes match for circuits that target different bits")
+	}
+}
@@ -2,20 +2,24 @@ package main
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"flag"
 	"log"
 	"os"
-	"strconv"
-	"strings"
+	"os/signal"
+	"runtime"
+	"sync"
+	"syscall"
+	"time"
 
+	"github.com/Drofff/revsynth-researcher/internal/researcher"
 	"github.com/Drofff/revsynth/aco"
 	"github.com/Drofff/revsynth/circuit"
 	"github.com/Drofff/revsynth/logging"
-	"github.com/aws/aws-sdk-go-v2/aws"
-	awsconf "github.com/aws/aws-sdk-go-v2/config"
-	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
-	awstypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
-	"github.com/google/uuid"
+	"golang.org/x/sync/errgroup"
 )
 
 type AlgConfig struct {
@@ -29,172 +33,362 @@ type AlgConfig struct {
 }
 
 type Input struct {
-	TargetQuantumCost int         `json:"targetQuantumCost"`
-	AcoConfigs        []AlgConfig `json:"acoConfigs"`
-	InputTT           [][]int     `json:"inputTT"`
-	TargetVector      []int       `json:"targetVector"`
+	TargetQuantumCost int            `json:"targetQuantumCost"`
+	AcoConfigs        []AlgConfig    `json:"acoConfigs"`
+	InputTT           [][]int        `json:"inputTT"`
+	TargetVector      []int          `json:"targetVector"`
+	Gates             []string       `json:"gates"`
+	GateCosts         map[string]int `json:"gateCosts"`
 }
 
-type Solution struct {
-	QuantumCost  int
-	TargetVector []int
-	Gates        []circuit.Gate
-}
+const depositStrengthDefault = 100
 
-type Repository interface {
-	SaveSolution(ctx context.Context, s Solution) error
-}
+func readInput(filename string) (Input, string) {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	in := &Input{}
+	err = json.Unmarshal(content, in)
+	if err != nil {
+		log.Fatalln(err)
+	}
 
-type ddbRepository struct {
-	ddbClient *dynamodb.Client
+	hash := sha256.Sum256(content)
+	return *in, hex.EncodeToString(hash[:])
 }
 
-const (
-	depositStrengthDefault = 100
+// runConfig synthesises a single AlgConfig against the input's truth
+// vector. It returns ok=false when the result should be discarded (no
+// synthesis found, or quantum cost above the target) rather than saved.
+func runConfig(in Input, acoConfig AlgConfig, configHash string, gateFactories []circuit.GateFactory, costModel GateCostModel) (sol researcher.Solution, ok bool, err error) {
+	conf := aco.Config{
+		NumOfAnts:       acoConfig.NumOfAnts,
+		NumOfIterations: acoConfig.NumOfIterations,
+		Alpha:           acoConfig.Alpha,
+		Beta:            acoConfig.Beta,
+		EvaporationRate: acoConfig.EvaporationRate,
+		DepositStrength: depositStrengthDefault,
+		LocalLoops:      acoConfig.LocalLoops,
+		SearchDepth:     acoConfig.SearchDepth,
+	}
 
-	dynamoDBTableName    = "revsynth-research-results"
-	dynamoDBPartitionKey = "id"
-	dynamoDBSortKey      = "truthVector"
-	dynamoDBQCKey        = "quantumCost"
-	dynamoDBGatesKey     = "gates"
-)
+	synth := aco.NewSynthesizer(conf, gateFactories, logging.NewLogger(logging.LevelInfo))
 
-func vectorToStr(v []int) string {
-	vss := make([]string, 0)
-	for _, el := range v {
-		vss = append(vss, strconv.Itoa(el))
+	res := synth.Synthesise(circuit.TruthVector{
+		Inputs: in.InputTT,
+		Vector: in.TargetVector,
+	})
+
+	if res.Complexity > 0 {
+		log.Println("Skipping as complexity is", res.Complexity)
+		return researcher.Solution{}, false, nil
 	}
 
-	return "[" + strings.Join(vss, ", ") + "]"
+	qc, err := costModel.CalcQuantumCost(res.Gates)
+	if err != nil {
+		return researcher.Solution{}, false, err
+	}
+	if qc > in.TargetQuantumCost {
+		log.Println("Skipping as quantum cost is", qc)
+		return researcher.Solution{}, false, nil
+	}
+
+	return researcher.Solution{
+		QuantumCost:  qc,
+		TargetVector: in.TargetVector,
+		Gates:        res.Gates,
+		ConfigHash:   configHash,
+		CircuitHash:  researcher.CanonicalCircuitHash(res.Gates),
+	}, true, nil
 }
 
-func gatesToStr(gates []circuit.Gate) string {
-	gatesSS := make([]string, 0)
+// configJob is a single AlgConfig paired with its position in
+// in.AcoConfigs and its content hash, so progress and coverage can be
+// tracked per config without the worker pool needing to know anything
+// about indices itself.
+type configJob struct {
+	index      int
+	config     AlgConfig
+	configHash string
+}
 
-	for i := len(gates) - 1; i >= 0; i-- {
-		gateS := gates[i].TypeName() + "(" + vectorToStr(gates[i].TargetBits()) + ", " + vectorToStr(gates[i].ControlBits()) + ")"
-		gatesSS = append(gatesSS, gateS)
+// progressTracker turns per-job completions, which arrive out of index
+// order because workers pull jobs from a shared pool, into a monotonic
+// high-water mark: the highest index N such that every job at or below N
+// has finished. Only that high-water mark is safe to persist, since
+// resuming from anything past it could skip a job still in flight if the
+// process crashes.
+type progressTracker struct {
+	mu        sync.Mutex
+	completed map[int]bool
+	next      int
+}
+
+func newProgressTracker(resumeFrom int) *progressTracker {
+	return &progressTracker{completed: make(map[int]bool), next: resumeFrom}
+}
+
+// markDone records that job index has finished and reports the new
+// high-water mark, if completing it advanced one. ok is false when index
+// was above the tracker's next expected index, meaning an earlier job is
+// still in flight and nothing can be persisted yet.
+func (t *progressTracker) markDone(index int) (highWater int, ok bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.completed[index] = true
+	highWater = t.next - 1
+	for t.completed[t.next] {
+		delete(t.completed, t.next)
+		highWater = t.next
+		t.next++
+		ok = true
 	}
+	return highWater, ok
+}
 
-	return strings.Join(gatesSS, ", ")
+// sweepMetrics tracks how a sweep run is going so progress can be
+// reported through the logger: how many solutions were saved, and how
+// many were skipped because they were already covered, duplicates, or
+// didn't meet the target.
+type sweepMetrics struct {
+	mu      sync.Mutex
+	started time.Time
+	saved   int
+	skipped int
 }
 
-func (r *ddbRepository) SaveSolution(ctx context.Context, s Solution) error {
-	id := uuid.NewString()
-	vectorS := vectorToStr(s.TargetVector)
-	gatesS := gatesToStr(s.Gates)
-
-	_, err := r.ddbClient.PutItem(ctx, &dynamodb.PutItemInput{
-		TableName: aws.String(dynamoDBTableName),
-		Item: map[string]awstypes.AttributeValue{
-			dynamoDBPartitionKey: &awstypes.AttributeValueMemberS{
-				Value: id,
-			},
-			dynamoDBSortKey: &awstypes.AttributeValueMemberS{
-				Value: vectorS,
-			},
-			dynamoDBQCKey: &awstypes.AttributeValueMemberN{
-				Value: strconv.Itoa(s.QuantumCost),
-			},
-			dynamoDBGatesKey: &awstypes.AttributeValueMemberS{
-				Value: gatesS,
-			},
-		},
-	})
-	return err
+func newSweepMetrics() *sweepMetrics {
+	return &sweepMetrics{started: time.Now()}
 }
 
-func readInput(filename string) Input {
-	content, err := os.ReadFile(filename)
-	if err != nil {
-		log.Fatalln(err)
-	}
+func (m *sweepMetrics) recordSaved() {
+	m.mu.Lock()
+	m.saved++
+	m.mu.Unlock()
+}
 
-	in := &Input{}
-	err = json.Unmarshal(content, in)
-	if err != nil {
-		log.Fatalln(err)
+func (m *sweepMetrics) recordSkipped() {
+	m.mu.Lock()
+	m.skipped++
+	m.mu.Unlock()
+}
+
+func (m *sweepMetrics) report(logger logging.Logger) {
+	m.mu.Lock()
+	saved, skipped := m.saved, m.skipped
+	elapsed := time.Since(m.started).Seconds()
+	m.mu.Unlock()
+
+	total := saved + skipped
+	skipRate := 0.0
+	if total > 0 {
+		skipRate = float64(skipped) / float64(total) * 100
 	}
 
-	return *in
+	logger.LogInfof("sweep progress: %.2f solutions/sec, %.1f%% skip rate (%d saved, %d skipped)\n",
+		float64(saved)/elapsed, skipRate, saved, skipped)
 }
 
-func createDDBRepository(ctx context.Context) Repository {
-	conf, err := awsconf.LoadDefaultConfig(ctx, func(opts *awsconf.LoadOptions) error {
-		opts.Region = "us-east-1"
+// runSweep fans the sweep's AlgConfigs out across a bounded pool of
+// workers. Results flow through a single channel so repo.SaveSolution
+// calls are serialised regardless of how many workers are producing
+// solutions concurrently. Already-covered (targetVector, config) pairs
+// and circuits that duplicate one already stored are skipped rather than
+// re-synthesised or re-saved; resumeFrom lets a restart skip configs a
+// prior run already finished.
+func runSweep(ctx context.Context, in Input, repo researcher.Repository, workers int, gateFactories []circuit.GateFactory, costModel GateCostModel, inputHash string, resumeFrom int, metrics *sweepMetrics, logger logging.Logger) error {
+	state, supportsState := repo.(researcher.SweepState)
+	progress := newProgressTracker(resumeFrom)
+
+	jobs := make(chan configJob)
+	solutions := make(chan struct {
+		sol   researcher.Solution
+		index int
+	})
+
+	g, ctx := errgroup.WithContext(ctx)
+
+	g.Go(func() error {
+		defer close(jobs)
+		for i, acoConfig := range in.AcoConfigs {
+			if i < resumeFrom {
+				continue
+			}
+
+			configHash, err := researcher.ConfigHash(acoConfig)
+			if err != nil {
+				return err
+			}
+
+			select {
+			case jobs <- configJob{index: i, config: acoConfig, configHash: configHash}:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
 		return nil
 	})
-	if err != nil {
-		log.Fatalln("Configure AWS:", err)
+
+	// advance persists the tracker's high-water mark for jobIndex, if
+	// completing it moved the mark forward. It is a no-op while an
+	// earlier job is still in flight, so a crash never resumes past a
+	// job that hasn't actually finished.
+	advance := func(jobIndex int) error {
+		if !supportsState {
+			return nil
+		}
+		if highWater, ok := progress.markDone(jobIndex); ok {
+			if err := state.SaveProgress(ctx, inputHash, highWater); err != nil {
+				return err
+			}
+		}
+		return nil
 	}
 
-	ddbClient := dynamodb.NewFromConfig(conf)
-	return &ddbRepository{ddbClient: ddbClient}
-}
+	for i := 0; i < workers; i++ {
+		g.Go(func() error {
+			for job := range jobs {
+				if supportsState {
+					covered, err := state.HasCoverage(ctx, in.TargetVector, job.configHash, in.TargetQuantumCost)
+					if err != nil {
+						return err
+					}
+					if covered {
+						log.Println("Skipping config, already covered")
+						metrics.recordSkipped()
+						if err := advance(job.index); err != nil {
+							return err
+						}
+						continue
+					}
+				}
+
+				log.Println("Running next config")
+				sol, ok, err := runConfig(in, job.config, job.configHash, gateFactories, costModel)
+				if err != nil {
+					return err
+				}
+				if !ok {
+					metrics.recordSkipped()
+					if err := advance(job.index); err != nil {
+						return err
+					}
+					continue
+				}
+
+				if supportsState {
+					duplicate, err := state.HasCircuit(ctx, sol.CircuitHash)
+					if err != nil {
+						return err
+					}
+					if duplicate {
+						log.Println("Skipping save, circuit already recorded")
+						metrics.recordSkipped()
+						if err := advance(job.index); err != nil {
+							return err
+						}
+						continue
+					}
+				}
+
+				select {
+				case solutions <- struct {
+					sol   researcher.Solution
+					index int
+				}{sol: sol, index: job.index}:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+			return nil
+		})
+	}
+
+	go func() {
+		g.Wait()
+		close(solutions)
+	}()
 
-func calcQuantumCost(gates []circuit.Gate) int {
-	qc := 0
-	for _, gate := range gates {
-		switch gate.TypeName() {
-		case "fredkin":
-			qc += 5
-		case "cnot":
-			qc += 1
-		default:
-			log.Fatalln("unknown gate type:", gate.TypeName())
+	for result := range solutions {
+		if err := repo.SaveSolution(ctx, result.sol); err != nil {
+			return err
+		}
+		metrics.recordSaved()
+
+		if err := advance(result.index); err != nil {
+			return err
 		}
 	}
-	return qc
+
+	return g.Wait()
 }
 
 func main() {
-	ctx := context.Background()
-	in := readInput("input.json")
-	repo := createDDBRepository(ctx)
-
-	for {
-		log.Println("Running next iteration")
-		for _, acoConfig := range in.AcoConfigs {
-			log.Println("Running next config")
-			conf := aco.Config{
-				NumOfAnts:       acoConfig.NumOfAnts,
-				NumOfIterations: acoConfig.NumOfIterations,
-				Alpha:           acoConfig.Alpha,
-				Beta:            acoConfig.Beta,
-				EvaporationRate: acoConfig.EvaporationRate,
-				DepositStrength: depositStrengthDefault,
-				LocalLoops:      acoConfig.LocalLoops,
-				SearchDepth:     acoConfig.SearchDepth,
-			}
+	workers := flag.Int("workers", runtime.NumCPU(), "number of ACO configs to run concurrently")
+	storage := flag.String("storage", "ddb", "storage backend to save solutions to (ddb|jsonl|sqlite|s3)")
+	jsonlPath := flag.String("jsonl-path", "solutions.jsonl", "path for the jsonl storage backend")
+	sqlitePath := flag.String("sqlite-path", "solutions.db", "path for the sqlite storage backend")
+	s3Bucket := flag.String("s3-bucket", "", "bucket for the s3 storage backend")
+	s3Prefix := flag.String("s3-prefix", "", "key prefix for the s3 storage backend")
+	ddbEndpoint := flag.String("ddb-endpoint", "", "override the DynamoDB endpoint, e.g. to use DynamoDB Local for CI")
+	ddbBatchSize := flag.Int("ddb-batch-size", 25, "buffer up to this many solutions before a BatchWriteItem; 0 disables batching")
+	flag.Parse()
 
-			synth := aco.NewSynthesizer(conf,
-				[]circuit.GateFactory{circuit.NewCnotGateFactory(), circuit.NewFredkinGateFactory()},
-				logging.NewLogger(logging.LevelInfo))
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-			res := synth.Synthesise(circuit.TruthVector{
-				Inputs: in.InputTT,
-				Vector: in.TargetVector,
-			})
-
-			if res.Complexity > 0 {
-				log.Println("Skipping as complexity is", res.Complexity)
-				continue
+	in, inputHash := readInput("input.json")
+	repo, err := researcher.CreateRepository(ctx, *storage, researcher.StorageConfig{
+		JSONLPath:        *jsonlPath,
+		SQLitePath:       *sqlitePath,
+		S3Bucket:         *s3Bucket,
+		S3Prefix:         *s3Prefix,
+		DDBEndpoint:      *ddbEndpoint,
+		DDBBatchSize:     *ddbBatchSize,
+		DDBFlushInterval: 5 * time.Second,
+	})
+	if err != nil {
+		log.Fatalln("Configure repository:", err)
+	}
+	if closer, ok := repo.(researcher.Closer); ok {
+		defer func() {
+			if err := closer.Close(context.Background()); err != nil {
+				log.Println("Close repository:", err)
 			}
+		}()
+	}
 
-			qc := calcQuantumCost(res.Gates)
-			if qc > in.TargetQuantumCost {
-				log.Println("Skipping as quantum cost is", qc)
-				continue
-			}
+	gateFactories, err := gateFactoriesFor(in.Gates)
+	if err != nil {
+		log.Fatalln("Configure gates:", err)
+	}
+	costModel := newGateCostModel(in.GateCosts)
+	logger := logging.NewLogger(logging.LevelInfo)
 
-			err := repo.SaveSolution(ctx, Solution{
-				QuantumCost:  qc,
-				TargetVector: in.TargetVector,
-				Gates:        res.Gates,
-			})
-			if err != nil {
-				log.Fatalln("Failed to save:", err)
+	resumeFrom := 0
+	if state, ok := repo.(researcher.SweepState); ok {
+		if idx, found, err := state.LoadProgress(ctx, inputHash); err != nil {
+			log.Fatalln("Load sweep progress:", err)
+		} else if found {
+			resumeFrom = idx + 1
+			log.Println("Resuming sweep from config index", resumeFrom)
+		}
+	}
+
+	metrics := newSweepMetrics()
+	for iteration := 0; ; iteration++ {
+		log.Println("Running next iteration")
+		if err := runSweep(ctx, in, repo, *workers, gateFactories, costModel, inputHash, resumeFrom, metrics, logger); err != nil {
+			if errors.Is(err, context.Canceled) {
+				log.Println("Sweep interrupted, flushing and shutting down")
+				return
 			}
+			log.Fatalln("Sweep failed:", err)
 		}
+		resumeFrom = 0
+		metrics.report(logger)
 	}
 }